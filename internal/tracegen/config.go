@@ -0,0 +1,145 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPause    = 10 * time.Microsecond
+	defaultDuration = time.Duration(0)
+	defaultWorkers  = 1
+	defaultService  = "tracegen"
+
+	defaultSamplingRefreshInterval = 10 * time.Second
+)
+
+// Config holds the configuration for the trace generator.
+type Config struct {
+	Workers    int
+	Traces     int
+	Duration   time.Duration
+	Pause      time.Duration
+	Service    string
+	Debug      bool
+	Firehose   bool
+	ChildSpans int
+
+	// Topology selects the shape of the simulated trace. It accepts one of
+	// the built-in presets ("linear", "fanout", "diamond") or a path to a
+	// YAML/JSON file describing a custom topology. When empty, tracegen
+	// falls back to its historical behavior of generating a single service
+	// with ChildSpans flat children.
+	Topology string
+
+	// Exporter selects the SpanExporter backend tracegen reports to.
+	// Defaults to ExporterStdout.
+	Exporter ExporterType
+	// Endpoint is the collector/receiver address for Exporter. Ignored by
+	// ExporterStdout.
+	Endpoint string
+	// Insecure disables TLS when talking to Endpoint.
+	Insecure bool
+	// Headers are extra key/value pairs sent with every export request,
+	// e.g. for collector authentication. Settable via repeated -header
+	// flags, e.g. -header Authorization=Bearer t0ken.
+	Headers map[string]string
+
+	// ServiceVersion is reported on the service.version resource attribute.
+	ServiceVersion string
+	// ResourceAttributes are additional arbitrary resource attributes
+	// merged onto every generated trace's Resource. Settable via repeated
+	// -resource-attribute flags, e.g. -resource-attribute deployment.environment=staging.
+	ResourceAttributes map[string]string
+
+	// SamplingServerURL is the base URL of a Jaeger agent/collector
+	// exposing the remote sampling HTTP API (e.g. http://localhost:5778).
+	// When set, workers poll it for Service's per-operation sampling
+	// strategies and use them to set sampling.priority on generated spans.
+	SamplingServerURL string
+	// SamplingRefreshInterval controls how often the sampling strategy is
+	// re-fetched from SamplingServerURL.
+	SamplingRefreshInterval time.Duration
+
+	// SDKConfigFile, when set, points to an opentelemetry-configuration
+	// YAML file used to build the TracerProvider via
+	// NewSDKFromConfigFile. Explicitly setting Exporter overrides it: see
+	// buildTracerProvider.
+	SDKConfigFile string
+
+	// ReplayFile, when set, points to a file of recorded traces (OTLP-JSON
+	// or Jaeger model JSON) that tracegen re-emits instead of generating
+	// synthetic ones. Workers/Traces still bound how many times it loops.
+	ReplayFile string
+	// TimeScale scales the relative timings of a replayed trace: 1 plays
+	// it back at its original speed, 2 plays it back twice as fast, 0.5
+	// half as fast. Defaults to 1 when unset or <= 0.
+	TimeScale float64
+}
+
+// Flags binds tracegen command line flags.
+func (c *Config) Flags(flags *flag.FlagSet) {
+	flags.IntVar(&c.Workers, "workers", defaultWorkers, "Number of workers simultaneously generating traces.")
+	flags.IntVar(&c.Traces, "traces", 0, "Number of traces to generate in each worker. If 0 there is no limit.")
+	flags.DurationVar(&c.Duration, "duration", defaultDuration, "Amount of time to run the test for. If 0 there is no limit.")
+	flags.DurationVar(&c.Pause, "pause", defaultPause, "How long to pause before starting the next trace per worker.")
+	flags.StringVar(&c.Service, "service", defaultService, "Service name to use")
+	flags.BoolVar(&c.Debug, "debug", false, "Whether to set DEBUG flag on the spans")
+	flags.BoolVar(&c.Firehose, "firehose", false, "Whether to set FIREHOSE flag on the spans")
+	flags.IntVar(&c.ChildSpans, "child-spans", 1, "Number of child spans to generate for each trace")
+	flags.StringVar(&c.Topology, "topology", "", "Topology to simulate: a preset name (linear, fanout, diamond) or a path to a topology file. Overrides -child-spans when set.")
+	flags.Var(&c.Exporter, "exporter", "Exporter backend to report spans to: stdout, otlp-grpc, otlp-http, jaeger-thrift, jaeger-grpc, zipkin")
+	flags.StringVar(&c.Endpoint, "otlp-endpoint", "", "Endpoint of the exporter backend, e.g. localhost:4317")
+	flags.BoolVar(&c.Insecure, "otlp-insecure", true, "Whether to disable TLS when dialing the exporter endpoint")
+	flags.Var(keyValueFlag{&c.Headers}, "header", "Extra key=value header sent with every export request (repeatable), e.g. -header Authorization='Bearer t0ken'")
+	flags.StringVar(&c.ServiceVersion, "service-version", "", "service.version resource attribute to report")
+	flags.Var(keyValueFlag{&c.ResourceAttributes}, "resource-attribute", "Extra key=value resource attribute merged onto every generated trace (repeatable), e.g. -resource-attribute deployment.environment=staging")
+	flags.StringVar(&c.SamplingServerURL, "sampling-server-url", "", "URL of the Jaeger remote sampling endpoint to follow, e.g. http://localhost:5778")
+	flags.DurationVar(&c.SamplingRefreshInterval, "sampling-refresh-interval", defaultSamplingRefreshInterval, "How often to re-fetch the sampling strategy from -sampling-server-url")
+	flags.StringVar(&c.SDKConfigFile, "sdk-config-file", "", "Path to an opentelemetry-configuration YAML file to build the TracerProvider from. Setting -exporter replaces it entirely; -service-version and -resource-attribute are merged onto its resource; no other flag overrides it.")
+	flags.StringVar(&c.ReplayFile, "replay-file", "", "Path to a file of recorded traces (OTLP-JSON or Jaeger model JSON) to replay instead of generating synthetic traces.")
+	flags.Float64Var(&c.TimeScale, "replay-time-scale", 1, "Speed multiplier applied to a replayed trace's relative timings.")
+}
+
+// Run returns true if the configuration describes a bounded or time-boxed
+// run, i.e. tracegen has a stopping condition other than being killed.
+func (c *Config) Run() bool {
+	return c.Traces > 0 || c.Duration > 0
+}
+
+// keyValueFlag implements flag.Value for a repeatable "-flag key=value"
+// flag that accumulates into a map[string]string, e.g. -header and
+// -resource-attribute in Flags.
+type keyValueFlag struct {
+	target *map[string]string
+}
+
+// String implements flag.Value.
+func (f keyValueFlag) String() string {
+	if f.target == nil || *f.target == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*f.target))
+	for k, v := range *f.target {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value.
+func (f keyValueFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	if *f.target == nil {
+		*f.target = make(map[string]string)
+	}
+	(*f.target)[k] = v
+	return nil
+}
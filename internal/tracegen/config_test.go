@@ -0,0 +1,39 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Config_Flags_HeadersAndResourceAttributes(t *testing.T) {
+	var cfg Config
+	flags := flag.NewFlagSet("tracegen", flag.ContinueOnError)
+	cfg.Flags(flags)
+
+	err := flags.Parse([]string{
+		"-header", "Authorization=Bearer t0ken",
+		"-header", "X-Scope-OrgID=tenant-a",
+		"-resource-attribute", "deployment.environment=staging",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"Authorization": "Bearer t0ken",
+		"X-Scope-OrgID": "tenant-a",
+	}, cfg.Headers)
+	assert.Equal(t, map[string]string{
+		"deployment.environment": "staging",
+	}, cfg.ResourceAttributes)
+}
+
+func Test_KeyValueFlag_Set_RejectsMissingEquals(t *testing.T) {
+	var target map[string]string
+	f := keyValueFlag{&target}
+	require.Error(t, f.Set("not-a-key-value-pair"))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// ExporterType names a supported tracegen exporter backend.
+type ExporterType string
+
+const (
+	ExporterStdout       ExporterType = "stdout"
+	ExporterOTLPGRPC     ExporterType = "otlp-grpc"
+	ExporterOTLPHTTP     ExporterType = "otlp-http"
+	ExporterJaegerThrift ExporterType = "jaeger-thrift"
+	ExporterJaegerGRPC   ExporterType = "jaeger-grpc"
+	ExporterZipkin       ExporterType = "zipkin"
+)
+
+// String implements flag.Value.
+func (e ExporterType) String() string {
+	if e == "" {
+		return string(ExporterStdout)
+	}
+	return string(e)
+}
+
+// Set implements flag.Value.
+func (e *ExporterType) Set(s string) error {
+	switch ExporterType(s) {
+	case ExporterStdout, ExporterOTLPGRPC, ExporterOTLPHTTP, ExporterJaegerThrift, ExporterJaegerGRPC, ExporterZipkin:
+		*e = ExporterType(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown exporter %q", s)
+	}
+}
+
+// newSpanExporter constructs the sdktrace.SpanExporter selected by
+// c.Exporter, dialing/connecting to c.Endpoint as needed.
+func newSpanExporter(ctx context.Context, c Config) (sdktrace.SpanExporter, error) {
+	switch c.Exporter {
+	case "", ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLPGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(c.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(c.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(c.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterJaegerThrift:
+		return jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(c.Endpoint)))
+	case ExporterJaegerGRPC:
+		// Jaeger's collector accepts OTLP/gRPC natively, so "jaeger-grpc"
+		// is the same wire protocol as otlp-grpc pointed at the
+		// collector's gRPC port.
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(c.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterZipkin:
+		return zipkin.New(c.Endpoint)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", c.Exporter)
+	}
+}
+
+// newResource builds the Resource reported alongside every span: the
+// service.name/service.version pair plus any user-supplied
+// ResourceAttributes.
+func newResource(c Config) *resource.Resource {
+	attrs := []attribute.KeyValue{semconv.ServiceName(c.Service)}
+	if c.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(c.ServiceVersion))
+	}
+	for k, v := range c.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// newTracerProvider wires up the SpanExporter selected by c.Exporter as a
+// BatchSpanProcessor on a freshly created TracerProvider, so spans are
+// flushed on Shutdown rather than emitted synchronously.
+func newTracerProvider(ctx context.Context, c Config) (*sdktrace.TracerProvider, error) {
+	exporter, err := newSpanExporter(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", c.Exporter, err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(newResource(c)),
+		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter)),
+	)
+	return tp, nil
+}
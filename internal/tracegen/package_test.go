@@ -0,0 +1,18 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// TestMain guards the whole package against goroutine leaks: every test
+// that starts a worker (directly or via simulateTraces) must let it run to
+// completion, including its background exporter/fetcher goroutines, before
+// returning.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
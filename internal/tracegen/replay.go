@@ -0,0 +1,186 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// recordedSpan is a single span read back from a replay file, normalized
+// from either the OTLP-JSON or Jaeger model JSON shape.
+type recordedSpan struct {
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Start        time.Time
+	Duration     time.Duration
+	Attributes   map[string]string
+}
+
+// recordedTrace is the set of spans that made up a single original trace,
+// replayed together under a freshly generated trace ID.
+type recordedTrace struct {
+	Spans []recordedSpan
+}
+
+// replaySource is a parsed replay file: each iteration of
+// worker.simulateTraces replays the next of its traces in turn, wrapping
+// around after the last one.
+type replaySource struct {
+	Traces []recordedTrace
+}
+
+// loadReplaySource reads path and converts its traces into replaySource.
+// It accepts two shapes: OTLP-JSON (a top-level "resourceSpans" array) and
+// Jaeger model JSON (a top-level "data" array, the shape returned by the
+// query service's /api/traces endpoint). The format is detected from
+// whichever top-level key is present.
+func loadReplaySource(path string) (*replaySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file %s: %w", path, err)
+	}
+
+	var probe struct {
+		ResourceSpans json.RawMessage `json:"resourceSpans"`
+		Data          json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse replay file %s: %w", path, err)
+	}
+
+	switch {
+	case probe.ResourceSpans != nil:
+		var f otlpTraceFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse OTLP-JSON replay file %s: %w", path, err)
+		}
+		return &replaySource{Traces: convertOTLPTraceFile(f)}, nil
+	case probe.Data != nil:
+		var f jaegerModelFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse Jaeger model replay file %s: %w", path, err)
+		}
+		return &replaySource{Traces: convertJaegerModelFile(f)}, nil
+	default:
+		return nil, fmt.Errorf("replay file %s has neither a resourceSpans nor a data array", path)
+	}
+}
+
+// --- OTLP-JSON -----------------------------------------------------------
+
+type otlpTraceFile struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []otlpSpan `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpSpan struct {
+	TraceID           string `json:"traceId"`
+	SpanID            string `json:"spanId"`
+	ParentSpanID      string `json:"parentSpanId"`
+	Name              string `json:"name"`
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string `json:"endTimeUnixNano"`
+	Attributes        []struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	} `json:"attributes"`
+}
+
+func convertOTLPTraceFile(f otlpTraceFile) []recordedTrace {
+	byTrace := map[string][]recordedSpan{}
+	for _, rs := range f.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				start, _ := strconv.ParseInt(s.StartTimeUnixNano, 10, 64)
+				end, _ := strconv.ParseInt(s.EndTimeUnixNano, 10, 64)
+				attrs := make(map[string]string, len(s.Attributes))
+				for _, kv := range s.Attributes {
+					attrs[kv.Key] = kv.Value.StringValue
+				}
+				byTrace[s.TraceID] = append(byTrace[s.TraceID], recordedSpan{
+					SpanID:       s.SpanID,
+					ParentSpanID: s.ParentSpanID,
+					Name:         s.Name,
+					Start:        time.Unix(0, start),
+					Duration:     time.Duration(end - start),
+					Attributes:   attrs,
+				})
+			}
+		}
+	}
+	return spanMapToTraces(byTrace)
+}
+
+// --- Jaeger model JSON -----------------------------------------------------
+
+type jaegerModelFile struct {
+	Data []struct {
+		Spans []jaegerModelSpan `json:"spans"`
+	} `json:"data"`
+}
+
+type jaegerModelSpan struct {
+	TraceID       string `json:"traceID"`
+	SpanID        string `json:"spanID"`
+	OperationName string `json:"operationName"`
+	References    []struct {
+		RefType string `json:"refType"`
+		SpanID  string `json:"spanID"`
+	} `json:"references"`
+	StartTime int64 `json:"startTime"` // microseconds since epoch
+	Duration  int64 `json:"duration"`  // microseconds
+	Tags      []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+func convertJaegerModelFile(f jaegerModelFile) []recordedTrace {
+	byTrace := map[string][]recordedSpan{}
+	for _, trace := range f.Data {
+		for _, s := range trace.Spans {
+			var parent string
+			for _, ref := range s.References {
+				if ref.RefType == "CHILD_OF" {
+					parent = ref.SpanID
+					break
+				}
+			}
+			tags := make(map[string]string, len(s.Tags))
+			for _, tag := range s.Tags {
+				tags[tag.Key] = tag.Value
+			}
+			byTrace[s.TraceID] = append(byTrace[s.TraceID], recordedSpan{
+				SpanID:       s.SpanID,
+				ParentSpanID: parent,
+				Name:         s.OperationName,
+				Start:        time.UnixMicro(s.StartTime),
+				Duration:     time.Duration(s.Duration) * time.Microsecond,
+				Attributes:   tags,
+			})
+		}
+	}
+	return spanMapToTraces(byTrace)
+}
+
+// spanMapToTraces turns a traceID -> spans map into the []recordedTrace
+// slice replaySource expects. Map iteration order is irrelevant since each
+// trace is replayed as an independent unit.
+func spanMapToTraces(byTrace map[string][]recordedSpan) []recordedTrace {
+	traces := make([]recordedTrace, 0, len(byTrace))
+	for _, spans := range byTrace {
+		traces = append(traces, recordedTrace{Spans: spans})
+	}
+	return traces
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger/internal/testutils"
+)
+
+func Test_LoadReplaySource_JaegerModel(t *testing.T) {
+	src, err := loadReplaySource("testdata/replay_jaeger_model.json")
+	require.NoError(t, err)
+	require.Len(t, src.Traces, 1)
+	assert.Len(t, src.Traces[0].Spans, 2)
+}
+
+func Test_LoadReplaySource_UnknownShape(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/bad.json"
+	require.NoError(t, os.WriteFile(path, []byte(`{"foo":"bar"}`), 0o600))
+	_, err := loadReplaySource(path)
+	require.Error(t, err)
+}
+
+func Test_SimulateTraces_Replay(t *testing.T) {
+	src, err := loadReplaySource("testdata/replay_jaeger_model.json")
+	require.NoError(t, err)
+
+	logger, buf := testutils.NewLogger()
+	tp := sdktrace.NewTracerProvider()
+	tracers := []trace.Tracer{tp.Tracer("replay")}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	var running uint32 = 1
+	workerID := 9
+	numTraces := 3
+	worker := &worker{
+		logger:       logger,
+		tracers:      tracers,
+		replaySource: src,
+		wg:           &wg,
+		id:           workerID,
+		running:      &running,
+		Config: Config{
+			Traces:    numTraces,
+			TimeScale: 1000, // keep the test fast: the recorded spans' few milliseconds shrink to microseconds
+		},
+	}
+	expectedOutput := fmt.Sprintf(`{"level":"info","msg":"Worker %d generated %d traces"}`, workerID, numTraces) + "\n"
+	worker.simulateTraces(context.Background())
+	assert.Equal(t, expectedOutput, buf.String())
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// perOperationStrategyResponse is the shape of a single entry in
+// operationSampling.perOperationStrategies, as returned by the Jaeger
+// agent/collector's "/sampling?service=" HTTP API.
+type perOperationStrategyResponse struct {
+	Operation             string `json:"operation"`
+	ProbabilisticSampling struct {
+		SamplingRate float64 `json:"samplingRate"`
+	} `json:"probabilisticSampling"`
+}
+
+// samplingStrategyResponse mirrors the subset of
+// jaeger/thrift-gen/sampling.SamplingStrategyResponse that tracegen cares
+// about: the default probability/lower bound plus any per-operation
+// overrides.
+type samplingStrategyResponse struct {
+	OperationSampling struct {
+		DefaultSamplingProbability       float64                        `json:"defaultSamplingProbability"`
+		DefaultLowerBoundTracesPerSecond float64                        `json:"defaultLowerBoundTracesPerSecond"`
+		PerOperationStrategies           []perOperationStrategyResponse `json:"perOperationStrategies"`
+	} `json:"operationSampling"`
+}
+
+// operationStrategy is the resolved sampling decision inputs for a single
+// operation name.
+type operationStrategy struct {
+	probability float64
+	lowerBound  float64
+}
+
+// samplingStrategyFetcher polls a Jaeger remote sampling endpoint for a
+// service's per-operation strategies and serves the most recently fetched
+// copy to workers without blocking them on network calls.
+type samplingStrategyFetcher struct {
+	serverURL string
+	service   string
+	interval  time.Duration
+	client    *http.Client
+	logger    *zap.Logger
+
+	mu              sync.RWMutex
+	perOperation    map[string]operationStrategy
+	defaultStrategy operationStrategy
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newSamplingStrategyFetcher creates a fetcher for service's strategies,
+// polling serverURL every interval once Start is called.
+func newSamplingStrategyFetcher(logger *zap.Logger, serverURL, service string, interval time.Duration) *samplingStrategyFetcher {
+	return &samplingStrategyFetcher{
+		serverURL: serverURL,
+		service:   service,
+		interval:  interval,
+		client:    &http.Client{Timeout: 5 * time.Second},
+		logger:    logger,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start fetches the strategy once synchronously so the first trace already
+// benefits from it, then refreshes it in the background every interval
+// until Stop is called.
+func (f *samplingStrategyFetcher) Start(ctx context.Context) {
+	if err := f.refresh(ctx); err != nil {
+		f.logger.Warn("failed to fetch initial sampling strategy", zap.Error(err))
+	}
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := f.refresh(ctx); err != nil {
+					f.logger.Warn("failed to refresh sampling strategy", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop terminates the background refresh goroutine and waits for it to
+// exit.
+func (f *samplingStrategyFetcher) Stop() {
+	close(f.stop)
+	<-f.done
+}
+
+// refresh fetches and caches the current strategy for f.service.
+func (f *samplingStrategyFetcher) refresh(ctx context.Context) error {
+	u := fmt.Sprintf("%s/sampling?service=%s", f.serverURL, url.QueryEscape(f.service))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sampling endpoint returned status %d", resp.StatusCode)
+	}
+
+	var parsed samplingStrategyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return fmt.Errorf("failed to decode sampling strategy response: %w", err)
+	}
+
+	perOperation := make(map[string]operationStrategy, len(parsed.OperationSampling.PerOperationStrategies))
+	for _, s := range parsed.OperationSampling.PerOperationStrategies {
+		perOperation[s.Operation] = operationStrategy{
+			probability: s.ProbabilisticSampling.SamplingRate,
+			lowerBound:  parsed.OperationSampling.DefaultLowerBoundTracesPerSecond,
+		}
+	}
+
+	f.mu.Lock()
+	f.perOperation = perOperation
+	f.defaultStrategy = operationStrategy{
+		probability: parsed.OperationSampling.DefaultSamplingProbability,
+		lowerBound:  parsed.OperationSampling.DefaultLowerBoundTracesPerSecond,
+	}
+	f.mu.Unlock()
+	return nil
+}
+
+// strategyFor returns the cached strategy for operation, falling back to
+// the service-wide default strategy when operation has no specific entry.
+func (f *samplingStrategyFetcher) strategyFor(operation string) operationStrategy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if s, ok := f.perOperation[operation]; ok {
+		return s
+	}
+	return f.defaultStrategy
+}
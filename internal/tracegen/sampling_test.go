@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_SamplingStrategyFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-service", r.URL.Query().Get("service"))
+		_, _ = w.Write([]byte(`{
+			"operationSampling": {
+				"defaultSamplingProbability": 0.1,
+				"defaultLowerBoundTracesPerSecond": 1,
+				"perOperationStrategies": [
+					{"operation": "checkout", "probabilisticSampling": {"samplingRate": 1}}
+				]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	fetcher := newSamplingStrategyFetcher(zap.NewNop(), srv.URL, "my-service", time.Hour)
+	fetcher.Start(context.Background())
+	defer fetcher.Stop()
+
+	checkout := fetcher.strategyFor("checkout")
+	assert.Equal(t, 1.0, checkout.probability)
+
+	unknown := fetcher.strategyFor("unknown-op")
+	assert.Equal(t, 0.1, unknown.probability)
+}
+
+func Test_SamplingStrategyFetcher_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	fetcher := newSamplingStrategyFetcher(zap.NewNop(), srv.URL, "my-service", time.Hour)
+	err := fetcher.refresh(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/contrib/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewSDKFromConfigFile parses the OpenTelemetry declarative configuration
+// file at path (tracer provider, processors, exporters, resource and
+// samplers, per the opentelemetry-configuration schema) and builds the
+// TracerProvider it describes.
+//
+// The returned shutdown func must be called once tracegen is done
+// generating traces; it flushes and closes every processor/exporter the
+// file configured.
+//
+// See buildTracerProvider for how this is combined with the flag-driven
+// Config.Exporter/ServiceVersion/ResourceAttributes path: explicitly setting
+// -exporter overrides a config file given via -sdk-config-file wholesale,
+// while -service-version/-resource-attribute are overlaid onto it field by
+// field.
+func NewSDKFromConfigFile(ctx context.Context, path string) (*sdktrace.TracerProvider, func(context.Context) error, error) {
+	parsed, err := parseSDKConfigFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buildSDKFromParsedConfig(ctx, path, parsed)
+}
+
+// parseSDKConfigFile reads and parses the opentelemetry-configuration YAML
+// file at path, without building an SDK from it yet. It is split out from
+// NewSDKFromConfigFile so buildTracerProvider can overlay CLI overrides onto
+// the parsed document before the SDK is constructed.
+func parseSDKConfigFile(path string) (*config.OpenTelemetryConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SDK config file %s: %w", path, err)
+	}
+	parsed, err := config.ParseYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SDK config file %s: %w", path, err)
+	}
+	return parsed, nil
+}
+
+// buildSDKFromParsedConfig builds the TracerProvider described by parsed.
+// path is only used to annotate errors.
+func buildSDKFromParsedConfig(ctx context.Context, path string, parsed *config.OpenTelemetryConfiguration) (*sdktrace.TracerProvider, func(context.Context) error, error) {
+	sdk, err := config.NewSDK(config.WithOpenTelemetryConfig(*parsed))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build SDK from config file %s: %w", path, err)
+	}
+
+	tp, ok := sdk.TracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		return nil, nil, fmt.Errorf("SDK config file %s did not produce an sdktrace.TracerProvider", path)
+	}
+	return tp, sdk.Shutdown, nil
+}
+
+// overlayResourceAttributes applies cfg.ServiceVersion and
+// cfg.ResourceAttributes onto parsed.Resource, overriding any attribute the
+// config file already declares under the same name and appending the rest.
+// This is the override layer promised for -sdk-config-file: CLI flags win,
+// but only for resource attributes. cfg.Exporter/cfg.Endpoint/cfg.Headers
+// are NOT overlaid here, since the config file's exporter is a union type
+// (otlp grpc/http, zipkin, console, ...) with no single generic field to
+// aim an endpoint/header override at; buildTracerProvider instead treats
+// cfg.Exporter as an all-or-nothing switch away from the config file, and a
+// caller who needs different endpoint/headers with the config-file path
+// should edit the YAML file itself.
+func overlayResourceAttributes(parsed *config.OpenTelemetryConfiguration, cfg Config) {
+	if cfg.ServiceVersion == "" && len(cfg.ResourceAttributes) == 0 {
+		return
+	}
+	if parsed.Resource == nil {
+		parsed.Resource = &config.Resource{}
+	}
+
+	overrides := make(map[string]string, len(cfg.ResourceAttributes)+1)
+	for k, v := range cfg.ResourceAttributes {
+		overrides[k] = v
+	}
+	if cfg.ServiceVersion != "" {
+		overrides["service.version"] = cfg.ServiceVersion
+	}
+
+	for name, value := range overrides {
+		replaced := false
+		for i, attr := range parsed.Resource.Attributes {
+			if attr.Name == name {
+				parsed.Resource.Attributes[i].Value = value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			parsed.Resource.Attributes = append(parsed.Resource.Attributes, config.AttributeNameValue{Name: name, Value: value})
+		}
+	}
+}
+
+// buildTracerProvider resolves Config into a TracerProvider, preferring
+// cfg.SDKConfigFile when set. -exporter is an all-or-nothing override: if
+// the caller explicitly set cfg.Exporter, it takes precedence and the
+// TracerProvider is built from the flag-driven newTracerProvider path
+// instead, so a single config file can be reused across runs while
+// individual invocations still swap the exporter. -service-version and
+// -resource-attribute, on the other hand, are overlaid onto the config file
+// rather than replacing it wholesale; see overlayResourceAttributes.
+func buildTracerProvider(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, func(context.Context) error, error) {
+	if cfg.SDKConfigFile != "" && cfg.Exporter == "" {
+		parsed, err := parseSDKConfigFile(cfg.SDKConfigFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		overlayResourceAttributes(parsed, cfg)
+		return buildSDKFromParsedConfig(ctx, cfg.SDKConfigFile, parsed)
+	}
+	tp, err := newTracerProvider(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tp, tp.Shutdown, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewSDKFromConfigFile(t *testing.T) {
+	tp, shutdown, err := NewSDKFromConfigFile(context.Background(), "testdata/otelconfig.yaml")
+	require.NoError(t, err)
+	require.NotNil(t, tp)
+	defer func() {
+		assert.NoError(t, shutdown(context.Background()))
+	}()
+
+	_, sp := tp.Tracer("sdk-config-test").Start(context.Background(), "span")
+	sp.End()
+}
+
+func Test_NewSDKFromConfigFile_MissingFile(t *testing.T) {
+	_, _, err := NewSDKFromConfigFile(context.Background(), "testdata/does-not-exist.yaml")
+	require.Error(t, err)
+}
+
+func Test_BuildTracerProvider_PrefersConfigFile(t *testing.T) {
+	tp, shutdown, err := buildTracerProvider(context.Background(), Config{
+		Service:       "tracegen-test",
+		SDKConfigFile: "testdata/otelconfig.yaml",
+	})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, shutdown(context.Background()))
+	}()
+	assert.NotNil(t, tp)
+}
+
+func Test_BuildTracerProvider_ExporterOverridesConfigFile(t *testing.T) {
+	tp, shutdown, err := buildTracerProvider(context.Background(), Config{
+		Service:       "tracegen-test",
+		SDKConfigFile: "testdata/otelconfig.yaml",
+		Exporter:      ExporterStdout,
+	})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, shutdown(context.Background()))
+	}()
+	assert.NotNil(t, tp)
+}
+
+func Test_BuildTracerProvider_OverlaysServiceVersionAndResourceAttributes(t *testing.T) {
+	tp, shutdown, err := buildTracerProvider(context.Background(), Config{
+		Service:            "tracegen-test",
+		SDKConfigFile:      "testdata/otelconfig.yaml",
+		ServiceVersion:     "1.2.3",
+		ResourceAttributes: map[string]string{"deployment.environment": "staging"},
+	})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, shutdown(context.Background()))
+	}()
+	assert.NotNil(t, tp)
+}
+
+func Test_OverlayResourceAttributes(t *testing.T) {
+	parsed, err := parseSDKConfigFile("testdata/otelconfig.yaml")
+	require.NoError(t, err)
+	require.NotNil(t, parsed.Resource)
+
+	overlayResourceAttributes(parsed, Config{
+		ServiceVersion:     "1.2.3",
+		ResourceAttributes: map[string]string{"deployment.environment": "staging", "service.name": "overridden"},
+	})
+
+	attrs := make(map[string]any, len(parsed.Resource.Attributes))
+	for _, a := range parsed.Resource.Attributes {
+		attrs[a.Name] = a.Value
+	}
+	assert.Equal(t, "overridden", attrs["service.name"])
+	assert.Equal(t, "1.2.3", attrs["service.version"])
+	assert.Equal(t, "staging", attrs["deployment.environment"])
+}
+
+func Test_OverlayResourceAttributes_NoOverridesIsNoop(t *testing.T) {
+	parsed, err := parseSDKConfigFile("testdata/otelconfig.yaml")
+	require.NoError(t, err)
+	before := len(parsed.Resource.Attributes)
+
+	overlayResourceAttributes(parsed, Config{Service: "tracegen-test"})
+
+	assert.Len(t, parsed.Resource.Attributes, before)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+// TopologyNode is a single service participating in a simulated trace.
+type TopologyNode struct {
+	Name string `yaml:"name" json:"name"`
+}
+
+// TopologyEdge describes a call from one node to another, e.g. an RPC or a
+// message being produced/consumed. Latency is modeled as a normal
+// distribution described by LatencyMean/LatencyStdDev.
+type TopologyEdge struct {
+	From          string        `yaml:"from" json:"from"`
+	To            string        `yaml:"to" json:"to"`
+	SpanKind      string        `yaml:"spanKind" json:"spanKind"`
+	RPCSystem     string        `yaml:"rpcSystem" json:"rpcSystem"`
+	LatencyMean   time.Duration `yaml:"latencyMean" json:"latencyMean"`
+	LatencyStdDev time.Duration `yaml:"latencyStdDev" json:"latencyStdDev"`
+	ErrorRate     float64       `yaml:"errorRate" json:"errorRate"`
+}
+
+// Topology is a directed graph of services exercised once per simulated
+// trace. Root is the node with no incoming edges; simulateTopologyTraces
+// walks the graph depth-first starting at Root.
+type Topology struct {
+	Root  string         `yaml:"root" json:"root"`
+	Nodes []TopologyNode `yaml:"nodes" json:"nodes"`
+	Edges []TopologyEdge `yaml:"edges" json:"edges"`
+}
+
+// spanKind maps the user-facing SpanKind string onto the OTel enum,
+// defaulting to SpanKindClient for unrecognized or empty values.
+func (e TopologyEdge) spanKind() trace.SpanKind {
+	switch strings.ToLower(e.SpanKind) {
+	case "server":
+		return trace.SpanKindServer
+	case "producer":
+		return trace.SpanKindProducer
+	case "consumer":
+		return trace.SpanKindConsumer
+	case "internal":
+		return trace.SpanKindInternal
+	default:
+		return trace.SpanKindClient
+	}
+}
+
+// edgesFrom returns the outgoing edges of node, in declaration order.
+func (t *Topology) edgesFrom(node string) []TopologyEdge {
+	var out []TopologyEdge
+	for _, e := range t.Edges {
+		if e.From == node {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// namedTopologyPresets are built-in topologies a user can select via
+// Config.Topology without having to write a topology file.
+var namedTopologyPresets = map[string]Topology{
+	"linear": {
+		Root: "frontend",
+		Nodes: []TopologyNode{
+			{Name: "frontend"}, {Name: "auth"}, {Name: "db"}, {Name: "cache"},
+		},
+		Edges: []TopologyEdge{
+			{From: "frontend", To: "auth", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 5 * time.Millisecond, LatencyStdDev: time.Millisecond},
+			{From: "auth", To: "db", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 3 * time.Millisecond, LatencyStdDev: time.Millisecond},
+			{From: "db", To: "cache", SpanKind: "client", RPCSystem: "grpc", LatencyMean: time.Millisecond, LatencyStdDev: 200 * time.Microsecond},
+		},
+	},
+	"fanout": {
+		Root: "frontend",
+		Nodes: []TopologyNode{
+			{Name: "frontend"}, {Name: "auth"}, {Name: "db"}, {Name: "cache"},
+		},
+		Edges: []TopologyEdge{
+			{From: "frontend", To: "auth", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 5 * time.Millisecond, LatencyStdDev: time.Millisecond},
+			{From: "frontend", To: "db", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 4 * time.Millisecond, LatencyStdDev: time.Millisecond, ErrorRate: 0.01},
+			{From: "frontend", To: "cache", SpanKind: "client", RPCSystem: "grpc", LatencyMean: time.Millisecond, LatencyStdDev: 200 * time.Microsecond},
+		},
+	},
+	"diamond": {
+		Root: "frontend",
+		Nodes: []TopologyNode{
+			{Name: "frontend"}, {Name: "auth"}, {Name: "cache"}, {Name: "db"},
+		},
+		Edges: []TopologyEdge{
+			{From: "frontend", To: "auth", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 5 * time.Millisecond, LatencyStdDev: time.Millisecond},
+			{From: "frontend", To: "cache", SpanKind: "client", RPCSystem: "grpc", LatencyMean: time.Millisecond, LatencyStdDev: 200 * time.Microsecond},
+			{From: "auth", To: "db", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 3 * time.Millisecond, LatencyStdDev: time.Millisecond},
+			{From: "cache", To: "db", SpanKind: "client", RPCSystem: "grpc", LatencyMean: 2 * time.Millisecond, LatencyStdDev: time.Millisecond, ErrorRate: 0.02},
+		},
+	},
+}
+
+// loadTopology resolves spec into a Topology. spec is either the name of a
+// built-in preset or a path to a YAML/JSON file with the same shape.
+func loadTopology(spec string) (*Topology, error) {
+	if preset, ok := namedTopologyPresets[spec]; ok {
+		t := preset
+		return &t, nil
+	}
+
+	data, err := os.ReadFile(spec)
+	if err != nil {
+		return nil, fmt.Errorf("topology %q is not a known preset and could not be read as a file: %w", spec, err)
+	}
+	var t Topology
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse topology file %s: %w", spec, err)
+	}
+	if t.Root == "" {
+		return nil, fmt.Errorf("topology file %s does not declare a root node", spec)
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("topology file %s is invalid: %w", spec, err)
+	}
+	return &t, nil
+}
+
+// validate rejects topologies that would make walkTopology recurse
+// forever, e.g. a cycle like frontend->auth, auth->frontend introduced by
+// a call-back/retry edge in a hand-written topology file.
+func (t *Topology) validate() error {
+	const (
+		white = iota // not yet visited
+		gray         // on the current DFS path
+		black        // fully explored, known cycle-free
+	)
+	color := make(map[string]int, len(t.Nodes))
+
+	var visit func(node string) error
+	visit = func(node string) error {
+		switch color[node] {
+		case black:
+			return nil
+		case gray:
+			return fmt.Errorf("cycle detected at node %q", node)
+		}
+		color[node] = gray
+		for _, e := range t.edgesFrom(node) {
+			if err := visit(e.To); err != nil {
+				return err
+			}
+		}
+		color[node] = black
+		return nil
+	}
+
+	return visit(t.Root)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Execute runs cfg.Workers workers until ctx is canceled, cfg.Duration
+// elapses, or every worker has generated cfg.Traces traces, whichever
+// comes first. It is the single place that turns a Config into running
+// workers.
+func Execute(ctx context.Context, cfg Config, logger *zap.Logger) error {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if !cfg.Run() {
+		logger.Info("No -traces or -duration bound set; tracegen will run until canceled")
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	tp, shutdown, err := buildTracerProvider(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create tracer provider: %w", err)
+	}
+	defer func() {
+		// Use a background context here, not ctx: ctx may already be
+		// canceled/timed out by the time workers finish, and the
+		// exporter still needs to flush whatever spans are queued.
+		if err := shutdown(context.Background()); err != nil {
+			logger.Warn("failed to shut down tracer provider", zap.Error(err))
+		}
+	}()
+
+	var topology *Topology
+	var nodeTracers map[string]trace.Tracer
+	if cfg.Topology != "" {
+		var err error
+		topology, err = loadTopology(cfg.Topology)
+		if err != nil {
+			return fmt.Errorf("failed to load topology %q: %w", cfg.Topology, err)
+		}
+		nodeTracers = make(map[string]trace.Tracer, len(topology.Nodes))
+		for _, n := range topology.Nodes {
+			nodeTracers[n.Name] = tp.Tracer(n.Name)
+		}
+	}
+
+	var samplingFetcher *samplingStrategyFetcher
+	if cfg.SamplingServerURL != "" {
+		interval := cfg.SamplingRefreshInterval
+		if interval <= 0 {
+			interval = defaultSamplingRefreshInterval
+		}
+		samplingFetcher = newSamplingStrategyFetcher(logger, cfg.SamplingServerURL, cfg.Service, interval)
+		samplingFetcher.Start(ctx)
+		defer samplingFetcher.Stop()
+	}
+
+	var replay *replaySource
+	if cfg.ReplayFile != "" {
+		var err error
+		replay, err = loadReplaySource(cfg.ReplayFile)
+		if err != nil {
+			return fmt.Errorf("failed to load replay file %q: %w", cfg.ReplayFile, err)
+		}
+	}
+
+	var running uint32 = 1
+	var wg sync.WaitGroup
+	tracers := []trace.Tracer{tp.Tracer(cfg.Service)}
+	wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		w := &worker{
+			Config:          cfg,
+			id:              i,
+			tracers:         tracers,
+			logger:          logger,
+			wg:              &wg,
+			running:         &running,
+			topology:        topology,
+			nodeTracers:     nodeTracers,
+			samplingFetcher: samplingFetcher,
+			replaySource:    replay,
+		}
+		go w.simulateTraces(ctx)
+	}
+
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/testutils"
+)
+
+func Test_Execute_Topology(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:  "tracegen-test",
+		Workers:  2,
+		Traces:   1,
+		Topology: "linear",
+	}, logger)
+	require.NoError(t, err)
+}
+
+func Test_Execute_RemoteSampling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"operationSampling":{"defaultSamplingProbability":1}}`))
+	}))
+	defer srv.Close()
+
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:           "tracegen-test",
+		Traces:            1,
+		SamplingServerURL: srv.URL,
+	}, logger)
+	require.NoError(t, err)
+}
+
+func Test_Execute_SDKConfigFile(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:       "tracegen-test",
+		Traces:        1,
+		SDKConfigFile: "testdata/otelconfig.yaml",
+	}, logger)
+	require.NoError(t, err)
+}
+
+func Test_Execute_ReplayFile(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:    "tracegen-test",
+		Traces:     1,
+		ReplayFile: "testdata/replay_jaeger_model.json",
+		TimeScale:  1000,
+	}, logger)
+	require.NoError(t, err)
+}
+
+func Test_Execute_UnknownReplayFile(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:    "tracegen-test",
+		Traces:     1,
+		ReplayFile: "testdata/does-not-exist.json",
+	}, logger)
+	require.Error(t, err)
+}
+
+func Test_Execute_UnknownTopology(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	err := Execute(context.Background(), Config{
+		Service:  "tracegen-test",
+		Traces:   1,
+		Topology: "not-a-real-preset-or-file",
+	}, logger)
+	require.Error(t, err)
+}
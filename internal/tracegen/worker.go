@@ -0,0 +1,398 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type worker struct {
+	Config
+
+	id      int
+	tracers []trace.Tracer
+	logger  *zap.Logger
+	wg      *sync.WaitGroup
+	running *uint32
+
+	// topology, when non-nil, switches simulateTraces into multi-service
+	// mode: each simulated trace walks the graph instead of generating a
+	// single service with ChildSpans flat children. nodeTracers supplies
+	// one trace.Tracer per TopologyNode.Name. Execute takes them all from
+	// the same TracerProvider, so they share its Resource and service.name
+	// and differ only in their instrumentation scope name.
+	topology    *Topology
+	nodeTracers map[string]trace.Tracer
+
+	// samplingFetcher, when non-nil, supplies the Jaeger remote sampling
+	// strategy consulted before starting each span's root/operation.
+	samplingFetcher *samplingStrategyFetcher
+
+	// replaySource, when non-nil, switches simulateTraces into replay
+	// mode: it re-emits the recorded traces instead of generating
+	// synthetic ones. Every replayed span goes through tracers[0].
+	replaySource *replaySource
+}
+
+// samplingAttributesFor consults w.samplingFetcher for operation and
+// returns the span attributes that reflect its decision: sampling.priority
+// when the coin flip favors keeping the span, and nothing otherwise. It
+// also reports whether that coin flip sampled the span, so callers can fold
+// the remote decision into other per-span choices (see simulateEdge's use
+// of it to pick a SpanKind). It returns a zero-value result with
+// sampled=true when no fetcher is configured, so tracegen behaves exactly
+// as if every operation were sampled when remote sampling isn't in use.
+func (w *worker) samplingAttributesFor(operation string) (attrs []attribute.KeyValue, forceFirehose bool, sampled bool) {
+	if w.samplingFetcher == nil {
+		return nil, false, true
+	}
+	strategy := w.samplingFetcher.strategyFor(operation)
+	sampled = rand.Float64() < strategy.probability
+	if sampled {
+		attrs = append(attrs, attribute.Int("sampling.priority", 1))
+	}
+	// A probability of 1 means the remote sampler wants every span for
+	// this operation kept in full, which tracegen mirrors by forcing the
+	// Firehose flag the same way a DEBUG-flagged production trace would.
+	forceFirehose = strategy.probability >= 1
+	return attrs, forceFirehose, sampled
+}
+
+// keepRunning returns true while ctx is not done and the worker hasn't been
+// asked to stop via the legacy atomic running flag.
+func (w *worker) keepRunning(ctx context.Context) bool {
+	return ctx.Err() == nil && atomic.LoadUint32(w.running) == 1
+}
+
+// sleep pauses for d or until ctx is canceled, whichever comes first. It
+// reports whether the full duration elapsed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// simulateTraces generates traces until ctx is canceled, the Duration or
+// number of Traces has been reached, or the worker is signaled to stop via
+// the legacy running flag. It always decrements wg exactly once, and never
+// returns with a span left un-ended, even when ctx is canceled mid-trace.
+func (w *worker) simulateTraces(ctx context.Context) {
+	defer w.wg.Done()
+
+	if w.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Duration)
+		defer cancel()
+	}
+
+	switch {
+	case w.replaySource != nil:
+		w.simulateReplayTraces(ctx)
+	case w.topology != nil:
+		w.simulateTopologyTraces(ctx)
+	default:
+		w.simulateFlatTraces(ctx)
+	}
+}
+
+// simulateFlatTraces is the historical single-service generator: one root
+// span per trace, with ChildSpans flat children under it.
+func (w *worker) simulateFlatTraces(ctx context.Context) {
+	tracer := w.tracers[rand.Intn(len(w.tracers))]
+
+	var i int
+	for w.keepRunning(ctx) {
+		w.emitFlatTrace(ctx, tracer)
+		i++
+
+		if w.Traces != 0 && i >= int(w.Traces) {
+			break
+		}
+		if !sleep(ctx, w.Pause) {
+			break
+		}
+	}
+
+	w.logger.Info(fmt.Sprintf("Worker %d generated %d traces", w.id, i))
+}
+
+// emitFlatTrace emits a single root span and its children. The root span
+// is always ended before returning, including when ctx is canceled while
+// generating children.
+func (w *worker) emitFlatTrace(ctx context.Context, tracer trace.Tracer) {
+	samplingAttrs, forceFirehose, _ := w.samplingAttributesFor("lets-go")
+	spanCtx, sp := tracer.Start(ctx, "lets-go")
+	defer sp.End()
+
+	if len(samplingAttrs) > 0 {
+		sp.SetAttributes(samplingAttrs...)
+	}
+	if w.Debug {
+		sp.SetAttributes(attribute.Bool("debug", true))
+	}
+	if w.Firehose || forceFirehose {
+		sp.SetAttributes(attribute.Bool("firehose", true))
+	}
+
+	for j := 0; j < w.ChildSpans && ctx.Err() == nil; j++ {
+		_, childSp := tracer.Start(spanCtx, fmt.Sprintf("child-span-%d", j))
+		sleep(ctx, w.Pause)
+		childSp.End()
+	}
+}
+
+// simulateTopologyTraces generates one trace per iteration by walking
+// w.topology depth-first from its root, starting a span per node and
+// propagating context across nodes the way a real RPC call would: the
+// parent injects a propagation.TraceContext carrier and the "remote" node
+// extracts it before starting its own span.
+func (w *worker) simulateTopologyTraces(ctx context.Context) {
+	propagator := propagation.TraceContext{}
+
+	var i int
+	for w.keepRunning(ctx) {
+		w.walkTopology(ctx, propagator, w.topology.Root, 0)
+		i++
+
+		if w.Traces != 0 && i >= int(w.Traces) {
+			break
+		}
+		if !sleep(ctx, w.Pause) {
+			break
+		}
+	}
+
+	w.logger.Info(fmt.Sprintf("Worker %d generated %d traces", w.id, i))
+}
+
+// maxTopologyDepth bounds how many hops walkTopology/simulateEdge will
+// recurse through. loadTopology already rejects cyclic topologies, so this
+// only matters as a last line of defense against a Topology built some
+// other way (or a future loader) that skips validation; without it, a
+// cycle would recurse until the goroutine's stack overflows and crashes
+// the whole process, not just the one worker.
+const maxTopologyDepth = 64
+
+// walkTopology starts a span for node and recurses into each outgoing edge,
+// carrying the parent context across the simulated network hop via
+// propagator. The span is always ended, even if ctx is canceled partway
+// through the node's edges.
+func (w *worker) walkTopology(ctx context.Context, propagator propagation.TraceContext, node string, depth int) {
+	if depth > maxTopologyDepth {
+		w.logger.Warn("topology recursion exceeded max depth, truncating trace", zap.Int("maxDepth", maxTopologyDepth))
+		return
+	}
+
+	tracer := w.nodeTracers[node]
+	if tracer == nil {
+		tracer = w.tracers[0]
+	}
+
+	samplingAttrs, forceFirehose, _ := w.samplingAttributesFor(node)
+	ctx, sp := tracer.Start(ctx, node)
+	defer sp.End()
+
+	if len(samplingAttrs) > 0 {
+		sp.SetAttributes(samplingAttrs...)
+	}
+	if w.Debug {
+		sp.SetAttributes(attribute.Bool("debug", true))
+	}
+	if w.Firehose || forceFirehose {
+		sp.SetAttributes(attribute.Bool("firehose", true))
+	}
+
+	for _, edge := range w.topology.edgesFrom(node) {
+		if ctx.Err() != nil {
+			return
+		}
+		w.simulateEdge(ctx, propagator, edge, depth)
+	}
+}
+
+// simulateEdge models a single call between two services: it starts a
+// client-side span on the caller's tracer carrying net.peer.*/rpc.system
+// attributes, injects it into a carrier, "transmits" it to the callee by
+// extracting it into a fresh context, and recurses into the callee node.
+// The span is always ended, even if ctx is canceled while the simulated
+// call is in flight.
+//
+// The edge's SpanKind normally comes straight from edge.spanKind(), but
+// when a remote sampling strategy is configured and it does not sample
+// edge.To, the edge is demoted to SpanKindInternal instead. Jaeger's real
+// "/sampling" response carries no SpanKind information, so this is
+// tracegen's own stand-in for "an unsampled call wouldn't really look like
+// a full RPC" rather than anything the remote sampler actually decides.
+func (w *worker) simulateEdge(ctx context.Context, propagator propagation.TraceContext, edge TopologyEdge, depth int) {
+	tracer := w.nodeTracers[edge.From]
+	if tracer == nil {
+		tracer = w.tracers[0]
+	}
+
+	kind := edge.spanKind()
+	if _, _, sampled := w.samplingAttributesFor(edge.To); !sampled {
+		kind = trace.SpanKindInternal
+	}
+
+	ctx, sp := tracer.Start(ctx, fmt.Sprintf("%s->%s", edge.From, edge.To), trace.WithSpanKind(kind))
+	defer sp.End()
+	sp.SetAttributes(
+		attribute.String("net.peer.name", edge.To),
+		attribute.String("rpc.system", edge.RPCSystem),
+	)
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	remoteCtx := propagator.Extract(ctx, carrier)
+
+	if !sleep(ctx, simulateLatency(edge.LatencyMean, edge.LatencyStdDev)) {
+		return
+	}
+
+	if edge.ErrorRate > 0 && rand.Float64() < edge.ErrorRate {
+		sp.SetStatus(codes.Error, fmt.Sprintf("simulated error calling %s", edge.To))
+	}
+
+	w.walkTopology(remoteCtx, propagator, edge.To, depth+1)
+}
+
+// simulateReplayTraces loops over w.replaySource.Traces, re-emitting each
+// one through tracers[0] with a fresh trace ID per iteration.
+func (w *worker) simulateReplayTraces(ctx context.Context) {
+	tracer := w.tracers[0]
+	scale := w.TimeScale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	var i int
+	traces := w.replaySource.Traces
+	for w.keepRunning(ctx) && len(traces) > 0 {
+		rt := traces[i%len(traces)]
+		replayTrace(ctx, tracer, rt, scale)
+		i++
+
+		if w.Traces != 0 && i >= int(w.Traces) {
+			break
+		}
+		if !sleep(ctx, w.Pause) {
+			break
+		}
+	}
+
+	w.logger.Info(fmt.Sprintf("Worker %d generated %d traces", w.id, i))
+}
+
+// replaySpanNode is a recordedSpan together with the children that
+// referenced it via CHILD_OF, used to walk a recorded trace in
+// parent-to-child order while re-emitting it.
+type replaySpanNode struct {
+	span     recordedSpan
+	children []*replaySpanNode
+}
+
+// replayTrace rebuilds rt's span tree and re-emits it through tracer,
+// preserving parent-child relationships and relative start offsets scaled
+// by scale. Root spans (no matching parent in the same trace) are started
+// directly off ctx. It returns once every span has been ended, including
+// when ctx is canceled mid-replay.
+func replayTrace(ctx context.Context, tracer trace.Tracer, rt recordedTrace, scale float64) {
+	nodes := make(map[string]*replaySpanNode, len(rt.Spans))
+	for i := range rt.Spans {
+		nodes[rt.Spans[i].SpanID] = &replaySpanNode{span: rt.Spans[i]}
+	}
+
+	var roots []*replaySpanNode
+	for _, n := range nodes {
+		if parent, ok := nodes[n.span.ParentSpanID]; ok && parent != n {
+			parent.children = append(parent.children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, root := range roots {
+		wg.Add(1)
+		go func(root *replaySpanNode) {
+			defer wg.Done()
+			replaySpan(ctx, tracer, root, scale)
+		}(root)
+	}
+	wg.Wait()
+}
+
+// replaySpan emits node's span and recurses into its children, delaying
+// each child's start by its recorded offset from node's own start time,
+// scaled by scale. The span is always ended before returning, even when
+// ctx is canceled before its children finish.
+func replaySpan(ctx context.Context, tracer trace.Tracer, node *replaySpanNode, scale float64) {
+	attrs := make([]attribute.KeyValue, 0, len(node.span.Attributes))
+	for k, v := range node.span.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	spanCtx, sp := tracer.Start(ctx, node.span.Name)
+	defer sp.End()
+	sp.SetAttributes(attrs...)
+
+	var wg sync.WaitGroup
+	for _, child := range node.children {
+		wg.Add(1)
+		go func(child *replaySpanNode) {
+			defer wg.Done()
+			if sleep(ctx, scaleDuration(child.span.Start.Sub(node.span.Start), scale)) {
+				replaySpan(spanCtx, tracer, child, scale)
+			}
+		}(child)
+	}
+
+	sleep(ctx, scaleDuration(node.span.Duration, scale))
+	wg.Wait()
+}
+
+// scaleDuration divides d by scale, clamping negative results (caused by
+// out-of-order timestamps in a replay file) to zero.
+func scaleDuration(d time.Duration, scale float64) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	scaled := time.Duration(float64(d) / scale)
+	if scaled < 0 {
+		return 0
+	}
+	return scaled
+}
+
+// simulateLatency draws a non-negative duration from a normal distribution
+// described by mean/stdDev. A zero mean yields no sleep at all.
+func simulateLatency(mean, stdDev time.Duration) time.Duration {
+	if mean <= 0 {
+		return 0
+	}
+	d := time.Duration(rand.NormFloat64()*float64(stdDev)) + mean
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -4,14 +4,21 @@
 package tracegen
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/goleak"
 
 	"github.com/jaegertracing/jaeger/internal/testutils"
 )
@@ -58,8 +65,194 @@ func Test_SimulateTraces(t *testing.T) {
 				},
 			}
 			expectedOutput := fmt.Sprintf(`{"level":"info","msg":"Worker %d generated %d traces"}`, workerID, numTraces) + "\n"
-			worker.simulateTraces()
+			worker.simulateTraces(context.Background())
 			assert.Equal(t, expectedOutput, buf.String())
 		})
 	}
 }
+
+func Test_SimulateTraces_Topology(t *testing.T) {
+	for _, preset := range []string{"linear", "fanout", "diamond"} {
+		t.Run(preset, func(t *testing.T) {
+			topology, err := loadTopology(preset)
+			require.NoError(t, err)
+
+			logger, buf := testutils.NewLogger()
+			tp := sdktrace.NewTracerProvider()
+			nodeTracers := map[string]trace.Tracer{}
+			for _, n := range topology.Nodes {
+				nodeTracers[n.Name] = tp.Tracer(n.Name)
+			}
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			var running uint32 = 1
+			workerID := 3
+			numTraces := 5
+			worker := &worker{
+				logger:      logger,
+				tracers:     []trace.Tracer{tp.Tracer(topology.Root)},
+				nodeTracers: nodeTracers,
+				topology:    topology,
+				wg:          &wg,
+				id:          workerID,
+				running:     &running,
+				Config: Config{
+					Traces:   numTraces,
+					Duration: time.Second,
+					Topology: preset,
+				},
+			}
+			expectedOutput := fmt.Sprintf(`{"level":"info","msg":"Worker %d generated %d traces"}`, workerID, numTraces) + "\n"
+			worker.simulateTraces(context.Background())
+			assert.Equal(t, expectedOutput, buf.String())
+		})
+	}
+}
+
+func Test_LoadTopology_UnknownPreset(t *testing.T) {
+	_, err := loadTopology("not-a-real-preset-or-file")
+	require.Error(t, err)
+}
+
+func Test_LoadTopology_RejectsCycle(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/cyclic.yaml"
+	require.NoError(t, os.WriteFile(path, []byte(`
+root: frontend
+nodes:
+  - name: frontend
+  - name: auth
+edges:
+  - from: frontend
+    to: auth
+  - from: auth
+    to: frontend
+`), 0o600))
+
+	_, err := loadTopology(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
+
+// Test_WalkTopology_DepthGuard is a defense-in-depth check: even if a
+// Topology bypasses loadTopology's cycle validation, walkTopology must not
+// recurse forever and crash the process.
+func Test_WalkTopology_DepthGuard(t *testing.T) {
+	topology := &Topology{
+		Root:  "frontend",
+		Nodes: []TopologyNode{{Name: "frontend"}, {Name: "auth"}},
+		Edges: []TopologyEdge{
+			{From: "frontend", To: "auth"},
+			{From: "auth", To: "frontend"},
+		},
+	}
+
+	logger, _ := testutils.NewLogger()
+	tp := sdktrace.NewTracerProvider()
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	var running uint32 = 1
+	worker := &worker{
+		logger:   logger,
+		tracers:  []trace.Tracer{tp.Tracer("stdout")},
+		topology: topology,
+		wg:       &wg,
+		id:       1,
+		running:  &running,
+		Config:   Config{Traces: 1},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		worker.simulateTraces(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("walkTopology did not return for a cyclic topology; depth guard is not working")
+	}
+}
+
+func Test_SamplingAttributesFor_NoFetcherSamplesEverything(t *testing.T) {
+	w := &worker{}
+	attrs, forceFirehose, sampled := w.samplingAttributesFor("op")
+	assert.Nil(t, attrs)
+	assert.False(t, forceFirehose)
+	assert.True(t, sampled)
+}
+
+// Test_SimulateEdge_DowngradesSpanKindWhenUnsampled checks that an edge
+// whose destination the remote sampling strategy declines to sample is
+// demoted to SpanKindInternal, per the doc comment on simulateEdge.
+func Test_SimulateEdge_DowngradesSpanKindWhenUnsampled(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	w := &worker{
+		tracers:  []trace.Tracer{tp.Tracer("stdout")},
+		topology: &Topology{},
+		samplingFetcher: &samplingStrategyFetcher{
+			defaultStrategy: operationStrategy{probability: 0},
+		},
+	}
+
+	edge := TopologyEdge{From: "frontend", To: "auth", SpanKind: "server"}
+	w.simulateEdge(context.Background(), propagation.TraceContext{}, edge, 0)
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	spans := exporter.GetSpans()
+	var edgeSpan *tracetest.SpanStub
+	for i, s := range spans {
+		if s.Name == "frontend->auth" {
+			edgeSpan = &spans[i]
+		}
+	}
+	require.NotNil(t, edgeSpan, "expected a frontend->auth span")
+	assert.Equal(t, trace.SpanKindInternal, edgeSpan.SpanKind)
+}
+
+// Test_SimulateTraces_CancelMidRun verifies that canceling ctx partway
+// through an unbounded run still lets simulateTraces return promptly, with
+// the WaitGroup decremented exactly once and no goroutines left behind.
+func Test_SimulateTraces_CancelMidRun(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	logger, _ := testutils.NewLogger()
+	tp := sdktrace.NewTracerProvider()
+	tracers := []trace.Tracer{tp.Tracer("stdout")}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	var running uint32 = 1
+	worker := &worker{
+		logger:  logger,
+		tracers: tracers,
+		wg:      &wg,
+		id:      1,
+		running: &running,
+		Config: Config{
+			// Traces left at 0 (unbounded) so the only way this run ends
+			// is via ctx cancellation.
+			Pause:      time.Millisecond,
+			ChildSpans: 1,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.simulateTraces(ctx)
+		close(done)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	atomic.StoreUint32(&running, 0)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("simulateTraces did not return after cancellation")
+	}
+	wg.Wait()
+}